@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/pocket-id/pocket-id/backend/internal/dto"
+	"github.com/pocket-id/pocket-id/backend/internal/model"
+	"github.com/pocket-id/pocket-id/backend/internal/service"
+)
+
+// NewWebhookController creates a new controller for managing webhook
+// subscriptions and registers its routes on the given admin-only group.
+func NewWebhookController(group *gin.RouterGroup, webhookService *service.WebhookService) {
+	wc := &WebhookController{webhookService: webhookService}
+
+	group.GET("/webhooks", wc.listWebhooksHandler)
+	group.POST("/webhooks", wc.createWebhookHandler)
+	group.GET("/webhooks/:id", wc.getWebhookHandler)
+	group.PUT("/webhooks/:id", wc.updateWebhookHandler)
+	group.DELETE("/webhooks/:id", wc.deleteWebhookHandler)
+	group.POST("/webhooks/:id/test", wc.testWebhookHandler)
+	group.GET("/webhooks/:id/deliveries", wc.listDeliveriesHandler)
+	group.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", wc.redeliverDeliveryHandler)
+}
+
+type WebhookController struct {
+	webhookService *service.WebhookService
+}
+
+func (wc *WebhookController) listWebhooksHandler(c *gin.Context) {
+	webhooks, err := wc.webhookService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	dtos := make([]dto.WebhookDto, len(webhooks))
+	for i, webhook := range webhooks {
+		dtos[i] = toWebhookDto(webhook)
+	}
+	c.JSON(http.StatusOK, dtos)
+}
+
+func (wc *WebhookController) getWebhookHandler(c *gin.Context) {
+	webhook, err := wc.webhookService.GetWebhook(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, toWebhookDto(*webhook))
+}
+
+func (wc *WebhookController) createWebhookHandler(c *gin.Context) {
+	var input dto.WebhookCreateDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+
+	webhook := model.Webhook{
+		Name:        input.Name,
+		Url:         input.Url,
+		Secret:      input.Secret,
+		ContentType: input.ContentType,
+		Format:      input.Format,
+		Template:    input.Template,
+		Headers:     model.WebhookHeaders(input.Headers),
+		Auth:        toWebhookAuthModel(input.Auth),
+		TLS:         toWebhookTLSModel(input.TLS),
+		Events:      model.WebhookEventList(input.Events),
+		Timeout:     input.Timeout,
+		MaxAttempts: input.MaxAttempts,
+		Active:      active,
+	}
+
+	created, err := wc.webhookService.CreateWebhook(c.Request.Context(), webhook)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, toWebhookDto(*created))
+}
+
+func (wc *WebhookController) updateWebhookHandler(c *gin.Context) {
+	var input dto.WebhookUpdateDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	update := model.Webhook{
+		Name:        input.Name,
+		Url:         input.Url,
+		ContentType: input.ContentType,
+		Format:      input.Format,
+		Template:    input.Template,
+		Headers:     model.WebhookHeaders(input.Headers),
+		Auth:        toWebhookAuthModel(input.Auth),
+		TLS:         toWebhookTLSModel(input.TLS),
+		Events:      model.WebhookEventList(input.Events),
+		Timeout:     input.Timeout,
+		MaxAttempts: input.MaxAttempts,
+		Active:      input.Active,
+	}
+
+	updated, err := wc.webhookService.UpdateWebhook(c.Request.Context(), c.Param("id"), update, input.Secret)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, toWebhookDto(*updated))
+}
+
+func (wc *WebhookController) deleteWebhookHandler(c *gin.Context) {
+	if err := wc.webhookService.DeleteWebhook(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (wc *WebhookController) testWebhookHandler(c *gin.Context) {
+	if err := wc.webhookService.SendTestWebhook(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (wc *WebhookController) listDeliveriesHandler(c *gin.Context) {
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	deliveries, err := wc.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	dtos := make([]dto.WebhookDeliveryDto, len(deliveries))
+	for i, delivery := range deliveries {
+		dtos[i] = toWebhookDeliveryDto(delivery)
+	}
+	c.JSON(http.StatusOK, dtos)
+}
+
+func (wc *WebhookController) redeliverDeliveryHandler(c *gin.Context) {
+	if err := wc.webhookService.RedeliverDelivery(c.Request.Context(), c.Param("deliveryId")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func toWebhookAuthModel(input dto.WebhookAuthDto) model.WebhookAuth {
+	return model.WebhookAuth{
+		Type:        input.Type,
+		Token:       input.Token,
+		Username:    input.Username,
+		Password:    input.Password,
+		HeaderName:  input.HeaderName,
+		HeaderValue: input.HeaderValue,
+	}
+}
+
+func toWebhookTLSModel(input dto.WebhookTLSInputDto) model.WebhookTLSConfig {
+	return model.WebhookTLSConfig{
+		InsecureSkipVerify: input.InsecureSkipVerify,
+		CACertPEM:          input.CACertPEM,
+		ClientCertPEM:      input.ClientCertPEM,
+		ClientKeyPEM:       input.ClientKeyPEM,
+	}
+}
+
+func toWebhookDto(webhook model.Webhook) dto.WebhookDto {
+	return dto.WebhookDto{
+		ID:          webhook.ID,
+		Name:        webhook.Name,
+		Url:         webhook.Url,
+		ContentType: webhook.ContentType,
+		Format:      webhook.Format,
+		Template:    webhook.Template,
+		Headers:     map[string]string(webhook.Headers),
+		AuthType:    webhook.Auth.Type,
+		TLS: dto.WebhookTLSDto{
+			InsecureSkipVerify: webhook.TLS.InsecureSkipVerify,
+			HasCACert:          webhook.TLS.CACertPEM != "",
+			HasClientCert:      webhook.TLS.ClientCertPEM != "" && webhook.TLS.ClientKeyPEM != "",
+		},
+		Events:      []string(webhook.Events),
+		Timeout:     webhook.Timeout,
+		MaxAttempts: webhook.MaxAttempts,
+		Active:      webhook.Active,
+		HasSecret:   webhook.Secret != "",
+	}
+}
+
+func toWebhookDeliveryDto(delivery model.WebhookDelivery) dto.WebhookDeliveryDto {
+	return dto.WebhookDeliveryDto{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		Event:         delivery.Event,
+		Attempt:       delivery.Attempt,
+		Status:        delivery.Status,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastStatus:    delivery.LastStatus,
+		LastError:     delivery.LastError,
+		CreatedAt:     delivery.CreatedAt,
+		DeliveredAt:   delivery.DeliveredAt,
+	}
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/pocket-id/pocket-id/backend/internal/model"
+)
+
+// WebhookDto is the API representation of a webhook. The secret and any auth
+// credentials are never included, only whether they're set.
+type WebhookDto struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Url         string                `json:"url"`
+	ContentType string                `json:"contentType"`
+	Format      model.WebhookFormat   `json:"format"`
+	Template    string                `json:"template,omitempty"`
+	Headers     map[string]string     `json:"headers,omitempty"`
+	AuthType    model.WebhookAuthType `json:"authType"`
+	TLS         WebhookTLSDto         `json:"tls"`
+	Events      []string              `json:"events"`
+	Timeout     int                   `json:"timeout"`
+	MaxAttempts int                   `json:"maxAttempts"`
+	Active      bool                  `json:"active"`
+	HasSecret   bool                  `json:"hasSecret"`
+}
+
+// WebhookTLSDto reports which TLS overrides a webhook has configured,
+// without echoing back certificate/key material.
+type WebhookTLSDto struct {
+	InsecureSkipVerify bool `json:"insecureSkipVerify"`
+	HasCACert          bool `json:"hasCaCert"`
+	HasClientCert      bool `json:"hasClientCert"`
+}
+
+// WebhookAuthDto is the payload accepted to set a webhook's auth scheme. A
+// zero value (absent "auth" object, or an explicit "type":"none") means no
+// auth, so Type is not required.
+type WebhookAuthDto struct {
+	Type        model.WebhookAuthType `json:"type" binding:"omitempty,oneof=none bearer basic header"`
+	Token       string                `json:"token,omitempty"`
+	Username    string                `json:"username,omitempty"`
+	Password    string                `json:"password,omitempty"`
+	HeaderName  string                `json:"headerName,omitempty"`
+	HeaderValue string                `json:"headerValue,omitempty"`
+}
+
+// WebhookTLSInputDto is the payload accepted to set a webhook's TLS overrides.
+type WebhookTLSInputDto struct {
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	CACertPEM          string `json:"caCertPem,omitempty"`
+	ClientCertPEM      string `json:"clientCertPem,omitempty"`
+	ClientKeyPEM       string `json:"clientKeyPem,omitempty"`
+}
+
+// WebhookCreateDto is the payload accepted to create a new webhook.
+type WebhookCreateDto struct {
+	Name        string              `json:"name" binding:"required"`
+	Url         string              `json:"url" binding:"required,url"`
+	Secret      string              `json:"secret"`
+	ContentType string              `json:"contentType"`
+	Format      model.WebhookFormat `json:"format" binding:"required,oneof=discord slack teams mattermost generic-json form-urlencoded custom"`
+	Template    string              `json:"template" binding:"required_if=Format custom"`
+	Headers     map[string]string   `json:"headers"`
+	Auth        WebhookAuthDto      `json:"auth"`
+	TLS         WebhookTLSInputDto  `json:"tls"`
+	Events      []string            `json:"events" binding:"required,min=1"`
+	Timeout     int                 `json:"timeout"`
+	MaxAttempts int                 `json:"maxAttempts"`
+	Active      *bool               `json:"active"`
+}
+
+// WebhookUpdateDto is the payload accepted to update an existing webhook.
+// Secret is a pointer so an empty string can be distinguished from "unset".
+type WebhookUpdateDto struct {
+	Name        string              `json:"name" binding:"required"`
+	Url         string              `json:"url" binding:"required,url"`
+	Secret      *string             `json:"secret"`
+	ContentType string              `json:"contentType"`
+	Format      model.WebhookFormat `json:"format" binding:"required,oneof=discord slack teams mattermost generic-json form-urlencoded custom"`
+	Template    string              `json:"template" binding:"required_if=Format custom"`
+	Headers     map[string]string   `json:"headers"`
+	Auth        WebhookAuthDto      `json:"auth"`
+	TLS         WebhookTLSInputDto  `json:"tls"`
+	Events      []string            `json:"events" binding:"required,min=1"`
+	Timeout     int                 `json:"timeout"`
+	MaxAttempts int                 `json:"maxAttempts"`
+	Active      bool                `json:"active"`
+}
+
+// WebhookDeliveryDto is the API representation of a single delivery attempt.
+type WebhookDeliveryDto struct {
+	ID            string                      `json:"id"`
+	WebhookID     string                      `json:"webhookId"`
+	Event         string                      `json:"event"`
+	Attempt       int                         `json:"attempt"`
+	Status        model.WebhookDeliveryStatus `json:"status"`
+	NextAttemptAt time.Time                   `json:"nextAttemptAt"`
+	LastStatus    int                         `json:"lastStatus"`
+	LastError     string                      `json:"lastError"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+	DeliveredAt   *time.Time                  `json:"deliveredAt"`
+}
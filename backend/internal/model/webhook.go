@@ -0,0 +1,154 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// WebhookFormat selects how a webhook payload is rendered on the wire. Each
+// built-in value maps to a template shipped with the service; Custom uses the
+// webhook's own Template instead.
+type WebhookFormat string
+
+const (
+	WebhookFormatDiscord        WebhookFormat = "discord"
+	WebhookFormatSlack          WebhookFormat = "slack"
+	WebhookFormatTeams          WebhookFormat = "teams"
+	WebhookFormatMattermost     WebhookFormat = "mattermost"
+	WebhookFormatGenericJSON    WebhookFormat = "generic-json"
+	WebhookFormatFormURLEncoded WebhookFormat = "form-urlencoded"
+	WebhookFormatCustom         WebhookFormat = "custom"
+)
+
+// WebhookHeaders is a set of extra headers sent with every delivery to a
+// webhook, whatever its format (e.g. an API key header some third-party
+// target expects), persisted as a JSON object in a single text column.
+type WebhookHeaders map[string]string
+
+// Scan implements sql.Scanner.
+func (h *WebhookHeaders) Scan(value any) error {
+	if value == nil {
+		*h = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type for WebhookHeaders: %T", value)
+	}
+
+	if len(raw) == 0 {
+		*h = nil
+		return nil
+	}
+
+	return json.Unmarshal(raw, h)
+}
+
+// Value implements driver.Valuer.
+func (h WebhookHeaders) Value() (driver.Value, error) {
+	if len(h) == 0 {
+		return "", nil
+	}
+	raw, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// WebhookEventList is a comma-separated event allow-list persisted as a single
+// text column. A bare "*" matches every event, and a "prefix.*" entry matches
+// any event name starting with "prefix.".
+type WebhookEventList []string
+
+// Scan implements sql.Scanner.
+func (l *WebhookEventList) Scan(value any) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("unsupported type for WebhookEventList: %T", value)
+	}
+
+	if raw == "" {
+		*l = nil
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	events := make(WebhookEventList, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			events = append(events, p)
+		}
+	}
+	*l = events
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (l WebhookEventList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Matches reports whether event is allowed by this list, honoring the "*"
+// wildcard and "prefix.*" patterns.
+func (l WebhookEventList) Matches(event string) bool {
+	if len(l) == 0 {
+		return false
+	}
+
+	for _, allowed := range l {
+		if allowed == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, ".*"); ok {
+			if strings.HasPrefix(event, prefix+".") {
+				return true
+			}
+			continue
+		}
+		if allowed == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Webhook is a single outbound notification target. A SendEvent fan-out
+// evaluates every active webhook whose Events list matches the audit event.
+type Webhook struct {
+	Base
+
+	Name        string           `json:"name"`
+	Url         string           `json:"url"`
+	Secret      string           `json:"-"`
+	ContentType string           `json:"contentType"`
+	Format      WebhookFormat    `json:"format"`
+	Template    string           `json:"template,omitempty" gorm:"type:text"` // text/template body; only used when Format is "custom"
+	Headers     WebhookHeaders   `json:"headers,omitempty" gorm:"type:text"`
+	Auth        WebhookAuth      `json:"auth,omitempty" gorm:"type:text"`
+	TLS         WebhookTLSConfig `json:"tls,omitempty" gorm:"type:text"`
+	Events      WebhookEventList `json:"events" gorm:"type:text"`
+	Timeout     int              `json:"timeout"`     // seconds; 0 means use the service default
+	MaxAttempts int              `json:"maxAttempts"` // 0 means use the dispatcher default
+	Active      bool             `json:"active"`
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// WebhookAuthType selects how outbound requests to a webhook authenticate.
+type WebhookAuthType string
+
+const (
+	WebhookAuthTypeNone   WebhookAuthType = "none"
+	WebhookAuthTypeBearer WebhookAuthType = "bearer"
+	WebhookAuthTypeBasic  WebhookAuthType = "basic"
+	WebhookAuthTypeHeader WebhookAuthType = "header"
+)
+
+// WebhookAuth describes the credentials used to authenticate outbound
+// requests to a webhook, persisted as a JSON object in a single text column.
+type WebhookAuth struct {
+	Type WebhookAuthType `json:"type"`
+
+	// Token is the bearer token sent as "Authorization: Bearer <token>".
+	Token string `json:"token,omitempty"`
+
+	// Username and Password are sent as HTTP basic auth.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// HeaderName and HeaderValue are sent as an arbitrary header, for targets
+	// that expect e.g. "X-Api-Key: <value>" instead of Authorization.
+	HeaderName  string `json:"headerName,omitempty"`
+	HeaderValue string `json:"headerValue,omitempty"`
+}
+
+// Scan implements sql.Scanner.
+func (a *WebhookAuth) Scan(value any) error {
+	if value == nil {
+		*a = WebhookAuth{}
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type for WebhookAuth: %T", value)
+	}
+
+	if len(raw) == 0 {
+		*a = WebhookAuth{}
+		return nil
+	}
+
+	return json.Unmarshal(raw, a)
+}
+
+// Value implements driver.Valuer.
+func (a WebhookAuth) Value() (driver.Value, error) {
+	if a.Type == "" || a.Type == WebhookAuthTypeNone {
+		return "", nil
+	}
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// WebhookTLSConfig carries per-webhook TLS overrides for targets behind a
+// private CA or that require mutual TLS.
+type WebhookTLSConfig struct {
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// CACertPEM, if set, is used instead of the system trust store to verify
+	// the target's certificate.
+	CACertPEM string `json:"caCertPem,omitempty"`
+
+	// ClientCertPEM and ClientKeyPEM, if both set, are presented to the
+	// target for mutual TLS.
+	ClientCertPEM string `json:"clientCertPem,omitempty"`
+	ClientKeyPEM  string `json:"clientKeyPem,omitempty"`
+}
+
+// IsZero reports whether no TLS override is configured, meaning the shared
+// default client can be used as-is.
+func (c WebhookTLSConfig) IsZero() bool {
+	return !c.InsecureSkipVerify && c.CACertPEM == "" && c.ClientCertPEM == "" && c.ClientKeyPEM == ""
+}
+
+// Scan implements sql.Scanner.
+func (c *WebhookTLSConfig) Scan(value any) error {
+	if value == nil {
+		*c = WebhookTLSConfig{}
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type for WebhookTLSConfig: %T", value)
+	}
+
+	if len(raw) == 0 {
+		*c = WebhookTLSConfig{}
+		return nil
+	}
+
+	return json.Unmarshal(raw, c)
+}
+
+// Value implements driver.Valuer.
+func (c WebhookTLSConfig) Value() (driver.Value, error) {
+	if c.IsZero() {
+		return "", nil
+	}
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
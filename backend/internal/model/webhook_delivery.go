@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// WebhookDeliveryStatus is the current state of a queued delivery attempt.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending    WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusProcessing WebhookDeliveryStatus = "processing"
+	WebhookDeliveryStatusDelivered  WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed     WebhookDeliveryStatus = "failed" // exhausted all attempts, or dropped as undeliverable
+)
+
+// WebhookDelivery is a single queued (and possibly retried) delivery of an
+// event payload to a webhook. SendEvent enqueues one row per matching
+// webhook; the dispatcher drains due rows and reschedules failures with
+// exponential backoff until MaxAttempts is reached.
+type WebhookDelivery struct {
+	Base
+
+	WebhookID     string                `json:"webhookId"`
+	Event         string                `json:"event"`
+	Payload       []byte                `json:"-" gorm:"type:blob"`
+	Attempt       int                   `json:"attempt"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	NextAttemptAt time.Time             `json:"nextAttemptAt"`
+	LastStatus    int                   `json:"lastStatus"`
+	LastError     string                `json:"lastError"`
+	DeliveredAt   *time.Time            `json:"deliveredAt"`
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/pocket-id/pocket-id/backend/internal/model"
+)
+
+// webhookClientCache lazily builds and reuses a dedicated *http.Client per
+// webhook that overrides TLS behavior (private CA, mTLS, or skip-verify),
+// so the certificate pool and key pair aren't rebuilt on every delivery.
+type webhookClientCache struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+func newWebhookClientCache() *webhookClientCache {
+	return &webhookClientCache{clients: make(map[string]*http.Client)}
+}
+
+// forget drops a cached client, e.g. after a webhook's TLS settings change.
+func (c *webhookClientCache) forget(webhookID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, webhookID)
+}
+
+// get returns the shared default client, or a cached per-webhook client built
+// from webhook.TLS if any override is configured.
+func (c *webhookClientCache) get(webhook model.Webhook, fallback *http.Client) (*http.Client, error) {
+	if webhook.TLS.IsZero() {
+		return fallback, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[webhook.ID]; ok {
+		return client, nil
+	}
+
+	tlsConfig, err := buildTLSConfig(webhook.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TLS config for webhook %s: %w", webhook.ID, err)
+	}
+
+	transport := fallback.Transport
+	baseTransport, ok := transport.(*http.Transport)
+	if !ok || baseTransport == nil {
+		baseTransport = http.DefaultTransport.(*http.Transport)
+	}
+	clonedTransport := baseTransport.Clone()
+	clonedTransport.TLSClientConfig = tlsConfig
+
+	client := &http.Client{
+		Transport: clonedTransport,
+		Timeout:   fallback.Timeout,
+	}
+	c.clients[webhook.ID] = client
+	return client, nil
+}
+
+// buildTLSConfig turns a WebhookTLSConfig into a *tls.Config, loading the CA
+// certificate and/or client key pair it references.
+func buildTLSConfig(cfg model.WebhookTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify} //nolint:gosec // explicit per-webhook opt-in
+
+	if cfg.CACertPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACertPEM)) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertPEM != "" && cfg.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCertPEM), []byte(cfg.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// applyAuth sets the request headers needed for webhook's configured auth scheme.
+func applyAuth(req *http.Request, auth model.WebhookAuth) {
+	switch auth.Type {
+	case model.WebhookAuthTypeBearer:
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case model.WebhookAuthTypeBasic:
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case model.WebhookAuthTypeHeader:
+		if auth.HeaderName != "" {
+			req.Header.Set(auth.HeaderName, auth.HeaderValue)
+		}
+	}
+}
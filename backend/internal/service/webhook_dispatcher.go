@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/pocket-id/pocket-id/backend/internal/model"
+)
+
+const (
+	// dispatcherPollInterval is how often the dispatcher looks for due deliveries.
+	dispatcherPollInterval = 5 * time.Second
+	// dispatcherWorkers bounds how many deliveries are sent concurrently.
+	dispatcherWorkers = 4
+	// dispatcherBatchSize is how many due deliveries are claimed per poll.
+	dispatcherBatchSize = 50
+	// defaultMaxAttempts is used when a webhook doesn't override it.
+	defaultMaxAttempts = 5
+	// staleProcessingTimeout is how long a delivery may sit in "processing"
+	// before the reaper assumes its worker died (crash, killed pod, ...) and
+	// requeues it.
+	staleProcessingTimeout = 2 * time.Minute
+	// reapPollInterval is how often the reaper looks for stale processing rows.
+	reapPollInterval = time.Minute
+	// maxWebhookTimeout bounds the per-hook request Timeout so that a single
+	// delivery attempt can never legitimately outlive staleProcessingTimeout.
+	// Without this, a slow webhook's still-in-flight attempt could get
+	// reaped and re-claimed by a second worker, double-delivering it.
+	maxWebhookTimeout = time.Minute
+)
+
+// backoffSchedule gives the base delay before each retry attempt. The last
+// entry is reused for any attempt beyond its index.
+var backoffSchedule = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+}
+
+// StartDispatcher runs the delivery worker pool until ctx is canceled, at
+// which point it waits for in-flight deliveries to finish before returning.
+func (s *WebhookService) StartDispatcher(ctx context.Context) {
+	jobs := make(chan model.WebhookDelivery, dispatcherWorkers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < dispatcherWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				s.attemptDelivery(ctx, delivery)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(dispatcherPollInterval)
+	defer ticker.Stop()
+
+	reapTicker := time.NewTicker(reapPollInterval)
+	defer reapTicker.Stop()
+
+	// Requeue anything left stuck in "processing" from before this start
+	// (e.g. the previous process crashed mid-delivery) before polling for
+	// newly due work.
+	s.reapStaleDeliveries(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return
+		case <-ticker.C:
+			s.claimDueDeliveries(ctx, jobs)
+		case <-reapTicker.C:
+			s.reapStaleDeliveries(ctx)
+		}
+	}
+}
+
+// reapStaleDeliveries requeues deliveries that have been stuck in
+// "processing" for longer than staleProcessingTimeout back to "pending" so
+// they're picked up again. A delivery can be orphaned in "processing" if its
+// worker is interrupted (process crash, or shutdown after claimDueDeliveries
+// has already flipped the row but before the worker finishes); without this,
+// such a delivery would never be retried.
+func (s *WebhookService) reapStaleDeliveries(ctx context.Context) {
+	cutoff := time.Now().Add(-staleProcessingTimeout)
+	result := s.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("status = ? AND updated_at <= ?", model.WebhookDeliveryStatusProcessing, cutoff).
+		Updates(map[string]any{
+			"status":          model.WebhookDeliveryStatusPending,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to reap stale webhook deliveries", slog.Any("error", result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		slog.WarnContext(ctx, "Requeued stale webhook deliveries stuck in processing", slog.Int64("count", result.RowsAffected))
+	}
+}
+
+// validateWebhookTimeout rejects a per-hook Timeout longer than
+// maxWebhookTimeout, which reapStaleDeliveries relies on to assume a
+// "processing" row is actually abandoned rather than just slow.
+func validateWebhookTimeout(webhook model.Webhook) error {
+	if webhook.Timeout <= 0 {
+		return nil
+	}
+	if time.Duration(webhook.Timeout)*time.Second > maxWebhookTimeout {
+		return fmt.Errorf("timeout must be %d seconds or less", int(maxWebhookTimeout.Seconds()))
+	}
+	return nil
+}
+
+// claimDueDeliveries loads deliveries whose next_attempt_at has passed,
+// marks them as processing so a slow poll tick can't pick them up twice,
+// and hands them to the worker pool.
+func (s *WebhookService) claimDueDeliveries(ctx context.Context, jobs chan<- model.WebhookDelivery) {
+	var due []model.WebhookDelivery
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryStatusPending, time.Now()).
+		Order("next_attempt_at asc").
+		Limit(dispatcherBatchSize).
+		Find(&due).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load due webhook deliveries", slog.Any("error", err))
+		return
+	}
+
+	for _, delivery := range due {
+		if err := s.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Update("status", model.WebhookDeliveryStatusProcessing).Error; err != nil {
+			slog.ErrorContext(ctx, "Failed to claim webhook delivery", slog.Any("error", err), slog.String("deliveryId", delivery.ID))
+			continue
+		}
+
+		select {
+		case jobs <- delivery:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// attemptDelivery sends one delivery attempt and either marks it delivered
+// or reschedules it with exponential backoff and jitter.
+func (s *WebhookService) attemptDelivery(ctx context.Context, delivery model.WebhookDelivery) {
+	webhook, err := s.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Dropping webhook delivery for deleted webhook",
+			slog.Any("error", err), slog.String("deliveryId", delivery.ID))
+		s.finishDelivery(ctx, delivery, model.WebhookDeliveryStatusFailed, 0, err)
+		return
+	}
+
+	var tmplCtx webhookTemplateContext
+	if err := json.Unmarshal(delivery.Payload, &tmplCtx); err != nil {
+		slog.ErrorContext(ctx, "Dropping webhook delivery with unreadable payload",
+			slog.Any("error", err), slog.String("deliveryId", delivery.ID))
+		s.finishDelivery(ctx, delivery, model.WebhookDeliveryStatusFailed, 0, err)
+		return
+	}
+
+	delivery.Attempt++
+
+	statusCode, retryAfter, sendErr := s.deliverPayload(ctx, *webhook, delivery.Event, delivery.ID, tmplCtx)
+	if sendErr == nil {
+		s.finishDelivery(ctx, delivery, model.WebhookDeliveryStatusDelivered, statusCode, nil)
+		return
+	}
+
+	maxAttempts := webhook.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	if delivery.Attempt >= maxAttempts {
+		slog.ErrorContext(ctx, "Webhook delivery exhausted all attempts",
+			slog.Any("error", sendErr), slog.String("deliveryId", delivery.ID), slog.Int("attempts", delivery.Attempt))
+		s.finishDelivery(ctx, delivery, model.WebhookDeliveryStatusFailed, statusCode, sendErr)
+		return
+	}
+
+	delay := nextBackoff(delivery.Attempt)
+	if retryAfter > 0 {
+		delay = retryAfter
+	}
+	delivery.NextAttemptAt = time.Now().Add(delay)
+	delivery.LastStatus = statusCode
+	delivery.LastError = sendErr.Error()
+	delivery.Status = model.WebhookDeliveryStatusPending
+
+	if err := s.db.WithContext(ctx).Save(&delivery).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to reschedule webhook delivery", slog.Any("error", err), slog.String("deliveryId", delivery.ID))
+	}
+}
+
+// finishDelivery persists a terminal outcome (delivered, exhausted, or
+// dropped because its webhook or payload can't be loaded) for a delivery.
+func (s *WebhookService) finishDelivery(ctx context.Context, delivery model.WebhookDelivery, status model.WebhookDeliveryStatus, statusCode int, sendErr error) {
+	delivery.Status = status
+	delivery.LastStatus = statusCode
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	}
+	if status == model.WebhookDeliveryStatusDelivered {
+		now := time.Now()
+		delivery.DeliveredAt = &now
+	}
+
+	if err := s.db.WithContext(ctx).Save(&delivery).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to finalize webhook delivery", slog.Any("error", err), slog.String("deliveryId", delivery.ID))
+	}
+}
+
+// nextBackoff returns the base delay for the given (1-indexed) attempt
+// number with up to 20% jitter to avoid thundering-herd retries.
+func nextBackoff(attempt int) time.Duration {
+	idx := attempt - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(backoffSchedule) {
+		idx = len(backoffSchedule) - 1
+	}
+	base := backoffSchedule[idx]
+
+	jitter := time.Duration(rand.Int64N(int64(base) / 5))
+	return base + jitter
+}
+
+// ListDeliveries returns the most recent deliveries for a webhook, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := s.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookID).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RedeliverDelivery requeues a delivery for an immediate retry, resetting its
+// attempt counter so it gets the full backoff schedule again.
+func (s *WebhookService) RedeliverDelivery(ctx context.Context, deliveryID string) error {
+	result := s.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]any{
+			"attempt":         0,
+			"status":          model.WebhookDeliveryStatusPending,
+			"next_attempt_at": time.Now(),
+			"delivered_at":    nil,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to redeliver webhook delivery: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("webhook delivery not found: %s", deliveryID)
+	}
+	return nil
+}
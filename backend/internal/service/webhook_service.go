@@ -7,174 +7,229 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
 	"github.com/pocket-id/pocket-id/backend/internal/model"
 )
 
 type WebhookService struct {
-	appConfigService *AppConfigService
-	httpClient       *http.Client
+	db          *gorm.DB
+	httpClient  *http.Client
+	clientCache *webhookClientCache
 }
 
-func NewWebhookService(appConfigService *AppConfigService, httpClient *http.Client) *WebhookService {
+func NewWebhookService(db *gorm.DB, httpClient *http.Client) *WebhookService {
 	return &WebhookService{
-		appConfigService: appConfigService,
-		httpClient:       httpClient,
+		db:          db,
+		httpClient:  httpClient,
+		clientCache: newWebhookClientCache(),
 	}
 }
 
-// Discord/Slack embed payload types
-
-type webhookEmbed struct {
-	Title     string              `json:"title"`
-	Color     int                 `json:"color"`
-	Fields    []webhookEmbedField `json:"fields"`
-	Timestamp string              `json:"timestamp"`
+// ListWebhooks returns every configured webhook.
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	if err := s.db.WithContext(ctx).Order("created_at asc").Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return webhooks, nil
 }
 
-type webhookEmbedField struct {
-	Name   string `json:"name"`
-	Value  string `json:"value"`
-	Inline bool   `json:"inline"`
+// GetWebhook returns a single webhook by id.
+func (s *WebhookService) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	var webhook model.Webhook
+	if err := s.db.WithContext(ctx).First(&webhook, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
 }
 
-type webhookPayload struct {
-	Content     string         `json:"content,omitempty"`
-	Embeds      []webhookEmbed `json:"embeds,omitempty"`      // Discord
-	Attachments []webhookEmbed `json:"attachments,omitempty"` // Slack
+// CreateWebhook persists a new webhook subscription.
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	if err := validateWebhookTemplate(webhook); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookTimeout(webhook); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return &webhook, nil
 }
 
-// SendEvent sends a webhook notification for an audit log event.
-// It checks if the webhook URL is configured and the event matches the filter.
-// This method is designed to be called asynchronously in a goroutine.
-func (s *WebhookService) SendEvent(ctx context.Context, auditLog model.AuditLog) {
-	cfg := s.appConfigService.GetDbConfig()
-	webhookURL := cfg.WebhookUrl.Value
-	if webhookURL == "" {
-		return
+// UpdateWebhook updates an existing webhook in place. If secret is nil, the
+// previously stored secret is left untouched.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id string, update model.Webhook, secret *string) (*model.Webhook, error) {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if the event matches the configured filter
-	if !s.isEventAllowed(string(auditLog.Event)) {
-		return
+	webhook.Name = update.Name
+	webhook.Url = update.Url
+	webhook.ContentType = update.ContentType
+	webhook.Format = update.Format
+	webhook.Template = update.Template
+	webhook.Headers = update.Headers
+	webhook.Auth = update.Auth
+	webhook.TLS = update.TLS
+	webhook.Events = update.Events
+	webhook.Timeout = update.Timeout
+	webhook.MaxAttempts = update.MaxAttempts
+	webhook.Active = update.Active
+	if secret != nil {
+		webhook.Secret = *secret
 	}
 
-	ipAddress := ""
-	if auditLog.IpAddress != nil {
-		ipAddress = *auditLog.IpAddress
+	if err := validateWebhookTemplate(*webhook); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookTimeout(*webhook); err != nil {
+		return nil, err
 	}
 
-	location := formatLocation(auditLog.Country, auditLog.City)
+	if err := s.db.WithContext(ctx).Save(webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	s.clientCache.forget(webhook.ID)
+	return webhook, nil
+}
 
-	payload := webhookPayload{
-		Embeds: []webhookEmbed{
-			{
-				Title: formatEventTitle(string(auditLog.Event)),
-				Color: 5814783, // A pleasant blue/purple color
-				Fields: []webhookEmbedField{
-					{Name: "User", Value: valueOrDash(auditLog.Username), Inline: true},
-					{Name: "IP Address", Value: valueOrDash(ipAddress), Inline: true},
-					{Name: "Location", Value: valueOrDash(location), Inline: true},
-					{Name: "Device", Value: valueOrDash(auditLog.UserAgent), Inline: true},
-				},
-				Timestamp: auditLog.CreatedAt.UTC().Format(time.RFC3339),
-			},
-		},
+// DeleteWebhook removes a webhook subscription.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	if err := s.db.WithContext(ctx).Delete(&model.Webhook{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
+	s.clientCache.forget(id)
+	return nil
+}
 
-	// Add any extra data fields from the audit log.
-	for k, v := range auditLog.Data {
-		payload.Embeds[0].Fields = append(payload.Embeds[0].Fields, webhookEmbedField{
-			Name:   k,
-			Value:  valueOrDash(v),
-			Inline: true,
-		})
+// SendEvent enqueues a delivery for every active webhook whose event
+// allow-list matches the audit log event. Deliveries are drained by the
+// dispatcher started with StartDispatcher, not sent inline, so a slow or
+// unreachable target can be retried without blocking the caller.
+// This method is designed to be called asynchronously in a goroutine.
+func (s *WebhookService) SendEvent(ctx context.Context, auditLog model.AuditLog) {
+	var webhooks []model.Webhook
+	if err := s.db.WithContext(ctx).Where("active = ?", true).Find(&webhooks).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to load webhooks", slog.Any("error", err))
+		return
 	}
 
-	err := s.sendPayload(ctx, webhookURL, payload)
+	tmplCtx := newTemplateContext(auditLog)
+	body, err := json.Marshal(tmplCtx)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to send webhook", slog.Any("error", err), slog.String("event", string(auditLog.Event)))
+		slog.ErrorContext(ctx, "Failed to marshal webhook event context", slog.Any("error", err))
+		return
 	}
-}
 
-// SendTestWebhook sends a test webhook to verify connectivity.
-func (s *WebhookService) SendTestWebhook(ctx context.Context) error {
-	cfg := s.appConfigService.GetDbConfig()
-	webhookURL := cfg.WebhookUrl.Value
-	if webhookURL == "" {
-		return fmt.Errorf("webhook URL is not configured")
-	}
-
-	payload := webhookPayload{
-		Embeds: []webhookEmbed{
-			{
-				Title: "Test Webhook",
-				Color: 5814783,
-				Fields: []webhookEmbedField{
-					{Name: "Status", Value: "Connection successful", Inline: false},
-					{Name: "Source", Value: "Pocket ID", Inline: true},
-				},
-				Timestamp: time.Now().UTC().Format(time.RFC3339),
-			},
-		},
-	}
-
-	return s.sendPayload(ctx, webhookURL, payload)
+	for _, webhook := range webhooks {
+		if !webhook.Events.Matches(string(auditLog.Event)) {
+			continue
+		}
+
+		delivery := model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         string(auditLog.Event),
+			Payload:       body,
+			Status:        model.WebhookDeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+		}
+		if err := s.db.WithContext(ctx).Create(&delivery).Error; err != nil {
+			slog.ErrorContext(ctx, "Failed to enqueue webhook delivery",
+				slog.Any("error", err), slog.String("webhookId", webhook.ID))
+		}
+	}
 }
 
-func (s *WebhookService) sendPayload(ctx context.Context, webhookURL string, payload webhookPayload) error {
-	var finalPayload any
-	if strings.Contains(webhookURL, "hooks.slack.com") {
-		payload.Attachments = payload.Embeds
-		payload.Embeds = nil
-		finalPayload = payload
-	} else {
-		finalPayload = payload
+// SendTestWebhook sends a test payload to the given webhook to verify connectivity.
+func (s *WebhookService) SendTestWebhook(ctx context.Context, webhookID string) error {
+	webhook, err := s.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook: %w", err)
 	}
 
-	body, err := json.Marshal(finalPayload)
+	tmplCtx := sampleTemplateContext()
+
+	_, _, err = s.deliverPayload(ctx, *webhook, tmplCtx.Event, uuid.NewString(), tmplCtx)
+	return err
+}
+
+// deliverPayload renders webhook's template against tmplCtx and performs a
+// single delivery attempt. deliveryID is sent as the X-Pocket-ID-Delivery
+// header so a consumer can dedupe retries of the same queued delivery; it
+// must stay stable across attempts. It returns the response status code (0
+// if the request never reached the server) so callers can decide how to
+// back off a failed attempt.
+func (s *WebhookService) deliverPayload(ctx context.Context, webhook model.Webhook, event string, deliveryID string, tmplCtx webhookTemplateContext) (statusCode int, retryAfter time.Duration, err error) {
+	body, contentType, err := renderWebhookBody(webhook, tmplCtx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+		return 0, 0, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create webhook request: %w", err)
+		return 0, 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Pocket-ID-Event", event)
+	req.Header.Set("X-Pocket-ID-Delivery", deliveryID)
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	applyAuth(req, webhook.Auth)
+
+	if webhook.Secret != "" {
+		digest, signature := signWebhookBody(webhook.Secret, body, time.Now())
+		req.Header.Set("Digest", digest)
+		req.Header.Set("X-Pocket-ID-Signature", signature)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	client, err := s.clientCache.get(webhook, s.httpClient)
+	if err != nil {
+		return 0, 0, err
+	}
+	if webhook.Timeout > 0 {
+		clientCopy := *client
+		clientCopy.Timeout = time.Duration(webhook.Timeout) * time.Second
+		client = &clientCopy
+	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send webhook request: %w", err)
+		return 0, 0, fmt.Errorf("failed to send webhook request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned non-success status: %d", resp.StatusCode)
+		return resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("webhook returned non-success status: %d", resp.StatusCode)
 	}
 
-	return nil
+	return resp.StatusCode, 0, nil
 }
 
-// isEventAllowed checks if the given event is in the configured event filter.
-// If no filter is configured (empty string), all events are allowed.
-func (s *WebhookService) isEventAllowed(event string) bool {
-	cfg := s.appConfigService.GetDbConfig()
-	eventsFilter := cfg.WebhookEvents.Value
-	if eventsFilter == "" {
-		return true
+// parseRetryAfter interprets a Retry-After header as either a delta-seconds
+// value or an HTTP date, returning 0 if it's absent or unparsable.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
 	}
-
-	for _, allowed := range strings.Split(eventsFilter, ",") {
-		if strings.TrimSpace(allowed) == event {
-			return true
-		}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
 	}
-
-	return false
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+	return 0
 }
 
 // formatEventTitle converts an event constant like "SIGN_IN" to a title like "Sign In"
@@ -200,10 +255,3 @@ func formatLocation(country, city string) string {
 	}
 	return city + ", " + country
 }
-
-func valueOrDash(v string) string {
-	if v == "" {
-		return "-"
-	}
-	return v
-}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifySignature_Valid(t *testing.T) {
+	secret := "test-secret"
+	body := []byte(`{"hello":"world"}`)
+
+	_, signature := signWebhookBody(secret, body, time.Now())
+
+	if err := VerifySignature(secret, signature, body); err != nil {
+		t.Fatalf("expected valid signature to verify, got error: %v", err)
+	}
+}
+
+func TestVerifySignature_TamperedBody(t *testing.T) {
+	secret := "test-secret"
+	body := []byte(`{"hello":"world"}`)
+
+	_, signature := signWebhookBody(secret, body, time.Now())
+
+	tampered := []byte(`{"hello":"world!"}`)
+	if err := VerifySignature(secret, signature, tampered); err == nil {
+		t.Fatal("expected tampered body to fail verification")
+	}
+}
+
+func TestVerifySignature_WrongSecret(t *testing.T) {
+	body := []byte(`{"hello":"world"}`)
+
+	_, signature := signWebhookBody("correct-secret", body, time.Now())
+
+	if err := VerifySignature("wrong-secret", signature, body); err == nil {
+		t.Fatal("expected signature signed with a different secret to fail verification")
+	}
+}
+
+func TestVerifySignature_TimestampSkew(t *testing.T) {
+	secret := "test-secret"
+	body := []byte(`{"hello":"world"}`)
+
+	_, signature := signWebhookBody(secret, body, time.Now().Add(-10*time.Minute))
+
+	if err := VerifySignature(secret, signature, body); err == nil {
+		t.Fatal("expected stale timestamp to be rejected")
+	}
+}
+
+func TestVerifySignature_MalformedHeader(t *testing.T) {
+	if err := VerifySignature("secret", "not-a-valid-header", []byte("body")); err == nil {
+		t.Fatal("expected malformed header to be rejected")
+	}
+}
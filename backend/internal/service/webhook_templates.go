@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pocket-id/pocket-id/backend/internal/model"
+)
+
+// webhookTemplateContext is the data made available to webhook body
+// templates, both built-in and user-defined.
+type webhookTemplateContext struct {
+	Event     string
+	Username  string
+	IpAddress string
+	Country   string
+	City      string
+	UserAgent string
+	CreatedAt time.Time
+	Data      map[string]string
+
+	// Convenience fields derived from the above, so templates don't all have
+	// to repeat the same formatting logic.
+	EventTitle string
+	Location   string
+	Timestamp  string
+}
+
+// newTemplateContext builds a template context from an audit log event.
+func newTemplateContext(auditLog model.AuditLog) webhookTemplateContext {
+	ipAddress := ""
+	if auditLog.IpAddress != nil {
+		ipAddress = *auditLog.IpAddress
+	}
+
+	return webhookTemplateContext{
+		Event:      string(auditLog.Event),
+		Username:   auditLog.Username,
+		IpAddress:  ipAddress,
+		Country:    auditLog.Country,
+		City:       auditLog.City,
+		UserAgent:  auditLog.UserAgent,
+		CreatedAt:  auditLog.CreatedAt,
+		Data:       auditLog.Data,
+		EventTitle: formatEventTitle(string(auditLog.Event)),
+		Location:   formatLocation(auditLog.Country, auditLog.City),
+		Timestamp:  auditLog.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// templateFuncs are available to every built-in and user-defined template.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	},
+	"default": func(def, val string) string {
+		if val == "" {
+			return def
+		}
+		return val
+	},
+	"title": func(s string) string {
+		words := strings.Fields(s)
+		for i, w := range words {
+			if len(w) > 0 {
+				words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+			}
+		}
+		return strings.Join(words, " ")
+	},
+}
+
+// builtinTemplates are the body templates used when a webhook doesn't supply
+// its own custom Template. Each renders a complete, valid JSON document.
+var builtinTemplates = map[model.WebhookFormat]*template.Template{
+	model.WebhookFormatDiscord:     template.Must(template.New("discord").Funcs(templateFuncs).Parse(discordTemplateSrc)),
+	model.WebhookFormatSlack:       template.Must(template.New("slack").Funcs(templateFuncs).Parse(slackTemplateSrc)),
+	model.WebhookFormatTeams:       template.Must(template.New("teams").Funcs(templateFuncs).Parse(teamsTemplateSrc)),
+	model.WebhookFormatMattermost:  template.Must(template.New("mattermost").Funcs(templateFuncs).Parse(mattermostTemplateSrc)),
+	model.WebhookFormatGenericJSON: template.Must(template.New("generic").Funcs(templateFuncs).Parse(genericTemplateSrc)),
+}
+
+const discordTemplateSrc = `{
+  "embeds": [
+    {
+      "title": {{json .EventTitle}},
+      "color": 5814783,
+      "fields": [
+        {"name": "User", "value": {{json (default "-" .Username)}}, "inline": true},
+        {"name": "IP Address", "value": {{json (default "-" .IpAddress)}}, "inline": true},
+        {"name": "Location", "value": {{json (default "-" .Location)}}, "inline": true},
+        {"name": "Device", "value": {{json (default "-" .UserAgent)}}, "inline": true}{{range $k, $v := .Data}},
+        {"name": {{json $k}}, "value": {{json $v}}, "inline": true}{{end}}
+      ],
+      "timestamp": {{json .Timestamp}}
+    }
+  ]
+}`
+
+const slackTemplateSrc = `{
+  "attachments": [
+    {
+      "title": {{json .EventTitle}},
+      "color": "#5899DF",
+      "fields": [
+        {"title": "User", "value": {{json (default "-" .Username)}}, "short": true},
+        {"title": "IP Address", "value": {{json (default "-" .IpAddress)}}, "short": true},
+        {"title": "Location", "value": {{json (default "-" .Location)}}, "short": true},
+        {"title": "Device", "value": {{json (default "-" .UserAgent)}}, "short": true}{{range $k, $v := .Data}},
+        {"title": {{json $k}}, "value": {{json $v}}, "short": true}{{end}}
+      ],
+      "ts": {{.CreatedAt.Unix}}
+    }
+  ]
+}`
+
+const teamsTemplateSrc = `{
+  "@type": "MessageCard",
+  "@context": "http://schema.org/extensions",
+  "themeColor": "5899DF",
+  "summary": {{json .EventTitle}},
+  "title": {{json .EventTitle}},
+  "sections": [
+    {
+      "facts": [
+        {"name": "User", "value": {{json (default "-" .Username)}}},
+        {"name": "IP Address", "value": {{json (default "-" .IpAddress)}}},
+        {"name": "Location", "value": {{json (default "-" .Location)}}},
+        {"name": "Device", "value": {{json (default "-" .UserAgent)}}}{{range $k, $v := .Data}},
+        {"name": {{json $k}}, "value": {{json $v}}}{{end}}
+      ]
+    }
+  ]
+}`
+
+const mattermostTemplateSrc = `{
+  "text": {{json .EventTitle}},
+  "attachments": [
+    {
+      "fields": [
+        {"title": "User", "value": {{json (default "-" .Username)}}, "short": true},
+        {"title": "IP Address", "value": {{json (default "-" .IpAddress)}}, "short": true},
+        {"title": "Location", "value": {{json (default "-" .Location)}}, "short": true},
+        {"title": "Device", "value": {{json (default "-" .UserAgent)}}, "short": true}{{range $k, $v := .Data}},
+        {"title": {{json $k}}, "value": {{json $v}}, "short": true}{{end}}
+      ]
+    }
+  ]
+}`
+
+const genericTemplateSrc = `{
+  "event": {{json .Event}},
+  "username": {{json .Username}},
+  "ipAddress": {{json .IpAddress}},
+  "country": {{json .Country}},
+  "city": {{json .City}},
+  "userAgent": {{json .UserAgent}},
+  "createdAt": {{json .Timestamp}},
+  "data": {{json .Data}}
+}`
+
+// renderWebhookBody renders the outbound body for a webhook, using its
+// custom Template when Format is "custom", a built-in template for the other
+// JSON formats, or a flattened form encoding for "form-urlencoded".
+func renderWebhookBody(webhook model.Webhook, ctx webhookTemplateContext) (body []byte, contentType string, err error) {
+	if webhook.Format == model.WebhookFormatFormURLEncoded {
+		return []byte(flattenContextToForm(ctx).Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	tmpl, err := resolveTemplate(webhook)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return nil, "", fmt.Errorf("failed to render webhook template: %w", err)
+	}
+
+	contentType = webhook.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return buf.Bytes(), contentType, nil
+}
+
+func resolveTemplate(webhook model.Webhook) (*template.Template, error) {
+	if webhook.Format == model.WebhookFormatCustom {
+		return parseCustomTemplate(webhook.Template)
+	}
+
+	if tmpl, ok := builtinTemplates[webhook.Format]; ok {
+		return tmpl, nil
+	}
+
+	return builtinTemplates[model.WebhookFormatGenericJSON], nil
+}
+
+// parseCustomTemplate parses a user-supplied template body with the same
+// funcs available to built-in templates.
+func parseCustomTemplate(src string) (*template.Template, error) {
+	tmpl, err := template.New("custom").Funcs(templateFuncs).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom webhook template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// sampleTemplateContext is a representative webhookTemplateContext used
+// anywhere a real audit log event isn't available: test deliveries and
+// template validation.
+func sampleTemplateContext() webhookTemplateContext {
+	now := time.Now()
+	return webhookTemplateContext{
+		Event:      "webhook.test",
+		EventTitle: "Test Webhook",
+		Username:   "Pocket ID",
+		Data:       map[string]string{"Status": "Connection successful"},
+		CreatedAt:  now,
+		Timestamp:  now.UTC().Format(time.RFC3339),
+	}
+}
+
+// validateWebhookTemplate rejects a webhook with a malformed custom
+// template. It's checked at create/update time so a template error never
+// makes it into the retry queue, where it would burn the full backoff
+// schedule as a non-retryable error masquerading as a transient one. The
+// template is both parsed and executed against a representative context,
+// since a typo'd field reference (or a missing Data key, with
+// missingkey=error) only surfaces on Execute, not Parse.
+func validateWebhookTemplate(webhook model.Webhook) error {
+	if webhook.Format != model.WebhookFormatCustom {
+		return nil
+	}
+	tmpl, err := parseCustomTemplate(webhook.Template)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Option("missingkey=error").Execute(io.Discard, sampleTemplateContext()); err != nil {
+		return fmt.Errorf("invalid custom webhook template: %w", err)
+	}
+	return nil
+}
+
+// flattenContextToForm encodes a template context as url.Values for targets
+// that expect application/x-www-form-urlencoded.
+func flattenContextToForm(ctx webhookTemplateContext) url.Values {
+	values := url.Values{}
+	values.Set("event", ctx.Event)
+	values.Set("title", ctx.EventTitle)
+	values.Set("username", ctx.Username)
+	values.Set("ipAddress", ctx.IpAddress)
+	values.Set("location", ctx.Location)
+	values.Set("userAgent", ctx.UserAgent)
+	values.Set("createdAt", ctx.Timestamp)
+	for k, v := range ctx.Data {
+		values.Set(k, v)
+	}
+	return values
+}